@@ -0,0 +1,155 @@
+package dspc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Transport moves serialized Progress deltas between processes for
+// Progress.Publish and Aggregator. Publish sends one message; Subscribe
+// registers fn to be called for every message received on the transport,
+// including ones published by the same process.
+type Transport interface {
+	Publish(data []byte) error
+	Subscribe(fn func(data []byte))
+}
+
+// publishMessage is the wire format sent by Progress.Publish and consumed by Aggregator.
+type publishMessage struct {
+	Publisher string           `json:"publisher"`
+	Seq       uint64           `json:"seq"`
+	Deltas    map[string]int64 `json:"deltas"`
+}
+
+// Publish periodically serializes the counters that changed since the last
+// tick and sends them over transport, tagged with publisherID and a
+// monotonically increasing sequence number. It blocks until ctx is canceled,
+// so it's typically run in its own goroutine alongside PrettyPrintEvery.
+//
+// Run one Publish per worker process, all sharing a Transport with an
+// Aggregator, to get one live merged view across machines or containers
+// while still using the regular Inc API on each worker's own Progress.
+func (p *Progress) Publish(ctx context.Context, transport Transport, publisherID string, interval time.Duration) error {
+	var seq uint64
+	last := map[string]int64{}
+
+	tick := func() error {
+		deltas := map[string]int64{}
+		for key, value := range p.All() {
+			if delta := value - last[key]; delta != 0 {
+				deltas[key] = delta
+				last[key] = value
+			}
+		}
+		if len(deltas) == 0 {
+			return nil
+		}
+
+		seq++
+		data, err := json.Marshal(publishMessage{Publisher: publisherID, Seq: seq, Deltas: deltas})
+		if err != nil {
+			return err
+		}
+		return transport.Publish(data)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := tick(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Aggregator subscribes to a Transport and merges the deltas published by
+// many Progress.Publish callers into a single synthetic *Progress, suitable
+// for display with PrettyPrintEvery.
+type Aggregator struct {
+	progress Progress
+
+	mu      sync.Mutex
+	lastSeq map[string]uint64
+}
+
+// NewAggregator subscribes to transport and returns an Aggregator whose
+// Progress reflects the sum of all counters seen across publishers.
+func NewAggregator(transport Transport) *Aggregator {
+	a := &Aggregator{lastSeq: map[string]uint64{}}
+	transport.Subscribe(a.handle)
+	return a
+}
+
+func (a *Aggregator) handle(data []byte) {
+	var msg publishMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Drop duplicate or out-of-order redeliveries from the transport.
+	if msg.Seq <= a.lastSeq[msg.Publisher] {
+		return
+	}
+	a.lastSeq[msg.Publisher] = msg.Seq
+
+	for key, delta := range msg.Deltas {
+		a.progress.Inc(key, delta)
+	}
+}
+
+// Progress returns the live, merged view. Its Get, All and PrettyPrintEvery
+// work exactly like a regular Progress fed by Inc.
+func (a *Aggregator) Progress() *Progress {
+	return &a.progress
+}
+
+// ChanTransport is an in-process Transport backed by a channel. It's useful
+// for tests, and for aggregating across goroutines within a single process
+// without a real network transport.
+type ChanTransport struct {
+	ch chan []byte
+
+	mu   sync.Mutex
+	subs []func([]byte)
+}
+
+// NewChanTransport returns a ready-to-use ChanTransport.
+func NewChanTransport() *ChanTransport {
+	t := &ChanTransport{ch: make(chan []byte, 64)}
+	go t.loop()
+	return t
+}
+
+func (t *ChanTransport) loop() {
+	for data := range t.ch {
+		t.mu.Lock()
+		subs := t.subs
+		t.mu.Unlock()
+
+		for _, fn := range subs {
+			fn(data)
+		}
+	}
+}
+
+func (t *ChanTransport) Publish(data []byte) error {
+	t.ch <- data
+	return nil
+}
+
+func (t *ChanTransport) Subscribe(fn func(data []byte)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.subs = append(t.subs, fn)
+}
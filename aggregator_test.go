@@ -0,0 +1,43 @@
+package dspc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAggregatorMergesPublishers(t *testing.T) {
+	transport := NewChanTransport()
+	agg := NewAggregator(transport)
+
+	var worker1, worker2 Progress
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go worker1.Publish(ctx, transport, "worker-1", 10*time.Millisecond)
+	go worker2.Publish(ctx, transport, "worker-2", 10*time.Millisecond)
+
+	worker1.Inc("done", 3)
+	worker2.Inc("done", 4)
+	worker2.Inc("errors", 1)
+
+	waitFor(t, func() bool {
+		return agg.Progress().Get("done") == 7 && agg.Progress().Get("errors") == 1
+	})
+}
+
+// waitFor polls cond until it's true or a short deadline passes.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("condition not met before deadline")
+}
@@ -0,0 +1,163 @@
+package dspc
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MetricsOptions configures MetricsHandler.
+type MetricsOptions struct {
+	// Prefix is prepended to every metric name, e.g. "myjob" turns the key
+	// "errors" into the metric "myjob_errors_total". Defaults to "dspc".
+	Prefix string
+
+	// Gauges lists keys that should be exposed as Prometheus gauges instead
+	// of counters. Keys are matched after Split, i.e. against the metric
+	// name, not the raw Progress key.
+	Gauges []string
+
+	// Split breaks a Progress key into a metric name and a set of labels.
+	// The default recognizes the "name[value]" convention used by Inc (see
+	// the package example, e.g. errors[timeout]) and turns it into name
+	// plus the label kind=value. Keys without a matching "[...]" suffix are
+	// passed through unchanged with no labels.
+	Split func(key string) (name string, labels map[string]string)
+}
+
+// MetricsHandler returns an http.Handler that renders every counter tracked
+// by p as a Prometheus text-format /metrics response. Each request reads a
+// fresh, atomic snapshot via p.All.
+func MetricsHandler(p *Progress, opts MetricsOptions) http.Handler {
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = "dspc"
+	}
+
+	split := opts.Split
+	if split == nil {
+		split = splitBracketKey
+	}
+
+	gauges := make(map[string]bool, len(opts.Gauges))
+	for _, key := range opts.Gauges {
+		gauges[sanitizeMetricName(key)] = true
+	}
+
+	type sample struct {
+		labels string
+		value  int64
+	}
+
+	type family struct {
+		isGauge bool
+		samples []sample
+	}
+
+	// families and order are scratch space reused across requests: once a
+	// scrape has seen every metric name at least once, later scrapes don't
+	// allocate a map or any sample/name slices at all, just reset and refill
+	// them. mu serializes scrapes, since the scratch space isn't safe for
+	// concurrent reuse (Progress itself stays lock-free; only this handler's
+	// reused buffers need the lock).
+	var mu sync.Mutex
+	families := make(map[string]*family)
+	var order []string
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		order = order[:0]
+		for _, f := range families {
+			f.samples = f.samples[:0]
+		}
+
+		for key, value := range p.All() {
+			rawName, labels := split(key)
+			rawName = sanitizeMetricName(rawName)
+			isGauge := gauges[rawName]
+
+			name := prefix + "_" + rawName
+			if !isGauge {
+				name += "_total"
+			}
+
+			f := families[name]
+			if f == nil {
+				f = &family{isGauge: isGauge}
+				families[name] = f
+			}
+			if len(f.samples) == 0 {
+				order = append(order, name)
+			}
+			f.samples = append(f.samples, sample{formatMetricLabels(labels), value})
+		}
+
+		slices.Sort(order)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		for _, name := range order {
+			f := families[name]
+
+			kind := "counter"
+			if f.isGauge {
+				kind = "gauge"
+			}
+
+			fmt.Fprintf(w, "# HELP %s %s tracked by dspc.Progress\n", name, name)
+			fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+			for _, s := range f.samples {
+				fmt.Fprintf(w, "%s%s %d\n", name, s.labels, s.value)
+			}
+		}
+	})
+}
+
+// splitBracketKey is the default MetricsOptions.Split. It turns a key like
+// "errors[timeout]" into the name "errors" and the label kind="timeout".
+// Keys without a "[...]" suffix are returned unchanged with no labels.
+func splitBracketKey(key string) (string, map[string]string) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 || !strings.HasSuffix(key, "]") {
+		return key, nil
+	}
+
+	return key[:open], map[string]string{"kind": key[open+1 : len(key)-1]}
+}
+
+// sanitizeMetricName replaces characters not allowed in Prometheus metric
+// and label names with "_".
+func sanitizeMetricName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+func formatMetricLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := slices.Sorted(maps.Keys(labels))
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", sanitizeMetricName(k), labels[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package dspc
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	var progress Progress
+	progress.Inc("done", 42)
+	progress.Inc("errors", 2)
+	progress.Inc("errors[timeout]", 1)
+	progress.Set("in_progress", 3)
+
+	handler := MetricsHandler(&progress, MetricsOptions{
+		Prefix: "myjob",
+		Gauges: []string{"in_progress"},
+	})
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE myjob_done_total counter",
+		"myjob_done_total 42",
+		`myjob_errors_total{kind="timeout"} 1`,
+		"myjob_errors_total 2",
+		"# TYPE myjob_in_progress gauge",
+		"myjob_in_progress 3",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsHandlerGaugeNameEndingInTotal(t *testing.T) {
+	var progress Progress
+	progress.Set("queue_total", 7)
+
+	handler := MetricsHandler(&progress, MetricsOptions{
+		Prefix: "myjob",
+		Gauges: []string{"queue_total"},
+	})
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+
+	// The metric name itself ends in "_total" even though it's a gauge; the
+	// TYPE line must still say gauge, not counter.
+	if !strings.Contains(body, "# TYPE myjob_queue_total gauge") {
+		t.Fatalf("expected gauge TYPE for myjob_queue_total, got:\n%s", body)
+	}
+}
+
+func TestSplitBracketKey(t *testing.T) {
+	name, labels := splitBracketKey("errors[timeout]")
+	expectValue(t, name, "errors")
+	expectValue(t, labels["kind"], "timeout")
+
+	name, labels = splitBracketKey("done")
+	expectValue(t, name, "done")
+	if labels != nil {
+		t.Fatalf("expected no labels, got %v", labels)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"iter"
 	"maps"
 	"os"
+	"strconv"
 	"sync/atomic"
 	"time"
 )
@@ -30,27 +31,101 @@ type Progress struct {
 }
 
 type progressState struct {
-	counters   map[string]*int64
+	counters   map[string]*counterSlot
 	sortedKeys []string
 }
 
+// counterSlot holds the storage for one key: either a single int64 (the
+// default, cheapest path) or a stripedCounter for keys under heavy
+// concurrent writes. Exactly one of plain/striped is non-nil for the
+// lifetime of the slot. striped is an atomic pointer (rather than a fixed
+// struct mutated in place) so Set can swap in a whole new, fully-formed
+// stripedCounter atomically: Get/All/prettyPrint summing over striped.cells
+// can never observe a torn, partially-reset state.
+//
+// kind and total carry the metadata set by Register/SetTotal, and the rate
+// fields below them cache the data prettyPrint needs to display a Rate or
+// Total column. Everything lives here so the printer stays lock-free: no
+// separate, mutex-guarded side table of per-key metadata.
+type counterSlot struct {
+	plain   *int64
+	striped atomic.Pointer[stripedCounter]
+
+	kind  atomic.Int32
+	total *int64
+
+	// rateState is only ever touched by the single goroutine driving
+	// PrettyPrintEvery, which calls prettyPrint/renderExtra serially.
+	rateState rateState
+}
+
+func newPlainSlot() *counterSlot {
+	return &counterSlot{plain: new(int64), total: new(int64)}
+}
+
+func newStripedSlot() *counterSlot {
+	slot := &counterSlot{total: new(int64)}
+	slot.striped.Store(newStripedCounter(defaultStripeCount()))
+	return slot
+}
+
+func (s *counterSlot) add(delta int64) {
+	if sc := s.striped.Load(); sc != nil {
+		sc.add(delta)
+		return
+	}
+	atomic.AddInt64(s.plain, delta)
+}
+
+func (s *counterSlot) store(value int64) {
+	if sc := s.striped.Load(); sc != nil {
+		// Swap in a fresh, fully-formed stripedCounter rather than zeroing
+		// cells one at a time in place, which would let a concurrent sum()
+		// observe a torn mix of old and zeroed cells.
+		fresh := newStripedCounter(len(sc.cells))
+		fresh.cells[0].v = value
+		s.striped.Store(fresh)
+		return
+	}
+	atomic.StoreInt64(s.plain, value)
+}
+
+func (s *counterSlot) load() int64 {
+	if sc := s.striped.Load(); sc != nil {
+		return sc.sum()
+	}
+	return atomic.LoadInt64(s.plain)
+}
+
 type entry struct {
 	key   string
 	value int64
+	extra string // e.g. "12.3/s" or "450/1000 (45%) ETA 1m30s"; empty for plain Counter/Gauge keys
 }
 
 // Inc atomically adds delta to the counter associated with the given key.
 // If the key doesn't exist, it's created with an initial value of 0 before adding delta.
 func (p *Progress) Inc(key string, delta int64) {
-	c := p.getOrCreateCounter(key)
-	atomic.AddInt64(c, delta)
+	slot := p.getOrCreateSlot(key, false)
+	slot.add(delta)
+}
+
+// IncStriped behaves like Inc, but stores key as a striped, LongAdder-style
+// counter: the value is spread across several padded cells so that many
+// goroutines hammering the same key don't contend on a single cache line.
+// Use it for hot keys identified under BenchmarkMultiThreaded-style load;
+// cold keys are cheaper with plain Inc. Once a key is striped it stays
+// striped, even if later incremented with Inc.
+func (p *Progress) IncStriped(key string, delta int64) {
+	slot := p.getOrCreateSlot(key, true)
+	slot.add(delta)
 }
 
 // Set atomically sets the counter associated with the given key to value.
 // If the key doesn't exist, it's created with the specified value.
 func (p *Progress) Set(key string, value int64) {
-	c := p.getOrCreateCounter(key)
-	atomic.StoreInt64(c, value)
+	slot := p.getOrCreateSlot(key, false)
+	slot.store(value)
 }
 
 // Get returns the current value of the counter associated with key.
@@ -61,12 +136,12 @@ func (p *Progress) Get(key string) int64 {
 		return 0
 	}
 
-	counter := state.counters[key]
-	if counter == nil {
+	slot := state.counters[key]
+	if slot == nil {
 		return 0
 	}
 
-	return atomic.LoadInt64(counter)
+	return slot.load()
 }
 
 // All returns an iterator over all counters in lexicographical key order.
@@ -80,7 +155,7 @@ func (p *Progress) All() iter.Seq2[string, int64] {
 		}
 
 		for _, key := range state.sortedKeys {
-			if !yield(key, atomic.LoadInt64(state.counters[key])) {
+			if !yield(key, state.counters[key].load()) {
 				return
 			}
 		}
@@ -96,38 +171,134 @@ func (p *Progress) size() int {
 	return len(state.counters)
 }
 
-func (p *Progress) getOrCreateCounter(key string) *int64 {
+// getOrCreateSlot returns the slot for key, creating it if necessary. If
+// striped is true and an existing slot is still plain, it's promoted to a
+// striped slot, preserving its current value.
+func (p *Progress) getOrCreateSlot(key string, striped bool) *counterSlot {
 	for {
 		state := p.state.Load()
 
-		// happy path: map contains the key
+		// happy path: map contains the key, and it's already the right kind
 		if state != nil {
-			if counter := state.counters[key]; counter != nil {
-				return counter
+			if slot := state.counters[key]; slot != nil {
+				if !striped || slot.striped.Load() != nil {
+					return slot
+				}
+
+				newSlot := newStripedSlot()
+				newSlot.striped.Load().cells[0].v = atomic.LoadInt64(slot.plain)
+				newSlot.kind.Store(slot.kind.Load())
+				atomic.StoreInt64(newSlot.total, atomic.LoadInt64(slot.total))
+
+				newState := cloneStateWithSlot(state, key, newSlot)
+				if p.state.CompareAndSwap(state, newState) {
+					return newSlot
+				}
+				continue
 			}
 		}
 
 		// Unhappy path: need to clone the state and add new key to it with CAS
-		newCounter := new(int64)
+		var newSlot *counterSlot
+		if striped {
+			newSlot = newStripedSlot()
+		} else {
+			newSlot = newPlainSlot()
+		}
+
 		newState := &progressState{}
 
 		if state != nil {
-			newState.counters = make(map[string]*int64, len(state.counters)+1)
+			newState.counters = make(map[string]*counterSlot, len(state.counters)+1)
 			maps.Copy(newState.counters, state.counters)
-			newState.counters[key] = newCounter
+			newState.counters[key] = newSlot
 
 			newState.sortedKeys = cloneSortedSliceAndInsert(state.sortedKeys, key)
 		} else {
-			newState.counters = map[string]*int64{key: newCounter}
+			newState.counters = map[string]*counterSlot{key: newSlot}
 			newState.sortedKeys = []string{key}
 		}
 
 		if p.state.CompareAndSwap(state, newState) {
-			return newCounter
+			return newSlot
 		}
 	}
 }
 
+// cloneStateWithSlot returns a copy of state with key's slot replaced by newSlot.
+// key must already be present in state.
+func cloneStateWithSlot(state *progressState, key string, newSlot *counterSlot) *progressState {
+	newState := &progressState{
+		counters:   make(map[string]*counterSlot, len(state.counters)),
+		sortedKeys: state.sortedKeys,
+	}
+	maps.Copy(newState.counters, state.counters)
+	newState.counters[key] = newSlot
+	return newState
+}
+
+// PrettyPrintFormat selects the output format used by PrettyPrintEveryWithOptions.
+type PrettyPrintFormat int
+
+const (
+	// FormatText renders progress as the human-readable, right-aligned table (the default).
+	FormatText PrettyPrintFormat = iota
+	// FormatJSON renders each snapshot as one JSON object per line, for downstream tooling.
+	FormatJSON
+)
+
+// InPlaceMode controls whether PrettyPrintEveryWithOptions redraws its
+// output in place using ANSI cursor movement. Whatever the mode, every
+// FormatText snapshot begins with the "\033[J" clear-to-end-of-screen escape;
+// only the trailing cursor-up escape depends on the mode.
+type InPlaceMode int
+
+const (
+	// InPlaceAuto redraws in place when w is an interactive terminal, and
+	// otherwise prints each snapshot after the previous one without the
+	// cursor-up escape (e.g. when w is a rotating log file). This is the zero
+	// value and default.
+	InPlaceAuto InPlaceMode = iota
+	// InPlaceAlways always redraws in place, regardless of what w is.
+	InPlaceAlways
+	// InPlaceNever never emits ANSI cursor-movement escapes.
+	InPlaceNever
+)
+
+// PrettyPrintEveryOptions configures PrettyPrintEveryWithOptions.
+type PrettyPrintEveryOptions struct {
+	InPlace InPlaceMode
+	Format  PrettyPrintFormat
+}
+
+func resolveInPlace(mode InPlaceMode, w io.Writer) bool {
+	switch mode {
+	case InPlaceAlways:
+		return true
+	case InPlaceNever:
+		return false
+	default:
+		return isTerminal(w)
+	}
+}
+
+// isTerminal reports whether w looks like an interactive terminal. It only
+// recognizes *os.File; any other io.Writer (a file sink, a bytes.Buffer, a
+// network connection) is treated as non-interactive.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
 func (p *Progress) prettyPrint(w io.Writer, title string, inPlace bool) error {
 	p.buf.Reset()
 	p.entries = p.entries[:0]
@@ -135,11 +306,19 @@ func (p *Progress) prettyPrint(w io.Writer, title string, inPlace bool) error {
 	maxKeySize := 0
 	maxValueSize := 0
 
-	for key, value := range p.All() {
-		p.entries = append(p.entries, entry{key, value})
+	now := time.Now()
 
-		maxKeySize = max(maxKeySize, len(key))
-		maxValueSize = max(maxValueSize, digitCount(value))
+	state := p.state.Load()
+	if state != nil {
+		for _, key := range state.sortedKeys {
+			slot := state.counters[key]
+			value := slot.load()
+
+			p.entries = append(p.entries, entry{key, value, slot.renderExtra(now, value)})
+
+			maxKeySize = max(maxKeySize, len(key))
+			maxValueSize = max(maxValueSize, digitCount(value))
+		}
 	}
 
 	// clear the screen after the cursor
@@ -160,6 +339,10 @@ func (p *Progress) prettyPrint(w io.Writer, title string, inPlace bool) error {
 		p.buf.WriteString("  ")
 		p.buf.WriteByteRepeated(' ', maxValueSize-digitCount(ent.value))
 		p.buf.WriteInt64(ent.value)
+		if ent.extra != "" {
+			p.buf.WriteString("  ")
+			p.buf.WriteString(ent.extra)
+		}
 		p.buf.WriteString("\n")
 	}
 
@@ -179,6 +362,37 @@ func (p *Progress) prettyPrint(w io.Writer, title string, inPlace bool) error {
 	return err
 }
 
+// prettyPrintJSON writes one JSON object snapshotting the current counters,
+// terminated by a newline (JSON-lines format), e.g.:
+//
+//	{"title":"Progress:","time":"2024-01-02T15:04:05Z","counters":{"done":15,"failed":3}}
+func (p *Progress) prettyPrintJSON(w io.Writer, title string) error {
+	p.buf.Reset()
+
+	p.buf.WriteString(`{"title":`)
+	p.buf.WriteString(strconv.Quote(title))
+	p.buf.WriteString(`,"time":`)
+	p.buf.WriteString(strconv.Quote(time.Now().Format(time.RFC3339)))
+	p.buf.WriteString(`,"counters":{`)
+
+	first := true
+	for key, value := range p.All() {
+		if !first {
+			p.buf.WriteString(",")
+		}
+		first = false
+
+		p.buf.WriteString(strconv.Quote(key))
+		p.buf.WriteString(":")
+		p.buf.WriteInt64(value)
+	}
+
+	p.buf.WriteString("}}\n")
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
 // PrettyPrintEvery periodically prints the current state of Progress to w (typically stdout ot stderr).
 // It updates the output in-place and won't damage the log output of the application
 // (assuming logs are printed line by line).
@@ -200,6 +414,20 @@ func (p *Progress) prettyPrint(w io.Writer, title string, inPlace bool) error {
 //	  failed      3
 //	  skipped     7
 func (p *Progress) PrettyPrintEvery(w io.Writer, t time.Duration, title string) func() {
+	return p.PrettyPrintEveryWithOptions(w, t, title, PrettyPrintEveryOptions{InPlace: InPlaceAlways})
+}
+
+// PrettyPrintEveryWithOptions is like PrettyPrintEvery but lets the caller
+// pick the rendering format and whether output is redrawn in place.
+//
+// InPlaceAuto (the zero value) is the right choice for a rotating file sink
+// such as AutoFile: it detects that w isn't a terminal and appends one
+// snapshot after another instead of emitting the cursor-up escape into the
+// file. FormatText snapshots still begin with the "\033[J" escape;
+// FormatJSON lines contain no escapes.
+func (p *Progress) PrettyPrintEveryWithOptions(w io.Writer, t time.Duration, title string, opts PrettyPrintEveryOptions) func() {
+	inPlace := resolveInPlace(opts.InPlace, w)
+
 	stop := make(chan struct{})
 	done := make(chan struct{})
 
@@ -208,13 +436,20 @@ func (p *Progress) PrettyPrintEvery(w io.Writer, t time.Duration, title string)
 		fmt.Fprintln(os.Stderr, "Error writing progress:", err)
 	}
 
+	print := func(final bool) error {
+		if opts.Format == FormatJSON {
+			return p.prettyPrintJSON(w, title)
+		}
+		return p.prettyPrint(w, title, inPlace && !final)
+	}
+
 	go func() {
 		defer close(done)
 
 		ticker := time.NewTicker(t)
 		defer ticker.Stop()
 
-		if err := p.prettyPrint(w, title, true); err != nil {
+		if err := print(false); err != nil {
 			printError(err)
 			return
 		}
@@ -222,13 +457,13 @@ func (p *Progress) PrettyPrintEvery(w io.Writer, t time.Duration, title string)
 		for {
 			select {
 			case <-ticker.C:
-				if err := p.prettyPrint(w, title, true); err != nil {
+				if err := print(false); err != nil {
 					printError(err)
 					return
 				}
 			case <-stop:
-				// w/o ansi
-				if err := p.prettyPrint(w, title, false); err != nil {
+				// final print, never in place
+				if err := print(true); err != nil {
 					printError(err)
 				}
 				return
@@ -0,0 +1,74 @@
+package dspc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrettyPrintJSON(t *testing.T) {
+	var progress Progress
+	progress.Inc("done", 5)
+	progress.Inc("failed", 1)
+
+	var buf bytes.Buffer
+	if err := progress.prettyPrintJSON(&buf, "Test progress:"); err != nil {
+		t.Fatalf("prettyPrintJSON: %v", err)
+	}
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+
+	var decoded struct {
+		Title    string           `json:"title"`
+		Time     string           `json:"time"`
+		Counters map[string]int64 `json:"counters"`
+	}
+	if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", line, err)
+	}
+
+	expectValue(t, decoded.Title, "Test progress:")
+	expectValue(t, decoded.Counters["done"], 5)
+	expectValue(t, decoded.Counters["failed"], 1)
+}
+
+func TestResolveInPlace(t *testing.T) {
+	var buf bytes.Buffer
+
+	expectValue(t, resolveInPlace(InPlaceAlways, &buf), true)
+	expectValue(t, resolveInPlace(InPlaceNever, &buf), false)
+	// InPlaceAuto over a non-*os.File writer is never in place.
+	expectValue(t, resolveInPlace(InPlaceAuto, &buf), false)
+}
+
+func TestPrettyPrintEveryWithOptionsJSON(t *testing.T) {
+	var progress Progress
+	progress.Inc("done", 1)
+
+	var out customWriter
+	outParts := make([]string, 0, 2)
+	enough := make(chan struct{})
+
+	stop := progress.PrettyPrintEveryWithOptions(&out, 20*time.Millisecond, "Test progress:", PrettyPrintEveryOptions{
+		Format: FormatJSON,
+	})
+
+	out.WriteFunc = func(p []byte) (int, error) {
+		outParts = append(outParts, string(p))
+		if len(outParts) == 1 {
+			close(enough)
+		}
+		return len(p), nil
+	}
+
+	<-enough
+	stop()
+
+	for _, part := range outParts {
+		if !strings.Contains(part, `"done":1`) {
+			t.Fatalf("expected JSON line to contain done counter, got %q", part)
+		}
+	}
+}
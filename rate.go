@@ -0,0 +1,143 @@
+package dspc
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Kind classifies how a registered key is rendered by prettyPrint.
+type Kind int
+
+const (
+	// Counter is a plain, ever-growing count. It's the default for any key
+	// that hasn't been passed to Register.
+	Counter Kind = iota
+	// Gauge is a value that can go up and down, e.g. "in_progress".
+	// It's displayed exactly like Counter; the distinction is informational.
+	Gauge
+	// Rate shows an EWMA items/sec figure next to the raw count.
+	Rate
+	// Total shows done/total, a percentage, and an ETA extrapolated from the
+	// current rate, once SetTotal has been called for the key.
+	Total
+)
+
+const rateSampleCount = 8
+
+type rateSample struct {
+	t     time.Time
+	value int64
+}
+
+// rateState tracks the data needed to compute a Rate/Total column for one
+// key. It's only ever touched by the single goroutine driving
+// PrettyPrintEvery (prettyPrint calls happen one at a time), so it needs no
+// synchronization of its own.
+type rateState struct {
+	samples [rateSampleCount]rateSample
+	count   int
+	head    int
+	ewma    float64
+}
+
+// sample records (now, value) and returns the current EWMA rate in units/sec.
+// It compares against the oldest sample still in the ring buffer (or the
+// first sample recorded, until the buffer fills), which smooths out the
+// jitter a single tick-to-tick delta would have.
+func (r *rateState) sample(now time.Time, value int64) float64 {
+	oldest := r.samples[r.head]
+	full := r.count == rateSampleCount
+
+	r.samples[r.head] = rateSample{now, value}
+	r.head = (r.head + 1) % rateSampleCount
+	if r.count < rateSampleCount {
+		r.count++
+	}
+
+	if r.count < 2 {
+		return 0
+	}
+
+	base := oldest
+	if !full {
+		base = r.samples[(r.head-r.count+rateSampleCount)%rateSampleCount]
+	}
+
+	dt := now.Sub(base.t).Seconds()
+	if dt <= 0 {
+		return r.ewma
+	}
+
+	instant := float64(value-base.value) / dt
+	if r.ewma == 0 {
+		r.ewma = instant
+	} else {
+		const alpha = 0.3
+		r.ewma = alpha*instant + (1-alpha)*r.ewma
+	}
+	return r.ewma
+}
+
+// Register declares the Kind of key, controlling how prettyPrint renders it.
+// Keys default to Counter; call Register before first use to opt a key into
+// Gauge, Rate, or Total display. Registering a key creates it if it doesn't
+// already exist, with an initial value of 0.
+func (p *Progress) Register(key string, kind Kind) {
+	slot := p.getOrCreateSlot(key, false)
+	slot.kind.Store(int32(kind))
+}
+
+// SetTotal attaches a target value to key, so prettyPrint can show its
+// done/total ratio, a percentage, and an ETA extrapolated from its current
+// rate. It also promotes key to Kind Total, unless it was already registered
+// as Rate.
+func (p *Progress) SetTotal(key string, total int64) {
+	slot := p.getOrCreateSlot(key, false)
+	atomic.StoreInt64(slot.total, total)
+
+	for {
+		old := Kind(slot.kind.Load())
+		if old == Rate || old == Total {
+			return
+		}
+		if slot.kind.CompareAndSwap(int32(old), int32(Total)) {
+			return
+		}
+	}
+}
+
+// renderExtra computes the column prettyPrint appends after a key's raw
+// value, e.g. "12.3/s" for a Rate key or "450/1000 (45%) ETA 1m30s" for a
+// Total key. It returns "" for Counter and Gauge keys.
+func (s *counterSlot) renderExtra(now time.Time, value int64) string {
+	switch Kind(s.kind.Load()) {
+	case Rate:
+		rate := s.rateState.sample(now, value)
+		return fmt.Sprintf("%.1f/s", rate)
+
+	case Total:
+		rate := s.rateState.sample(now, value)
+
+		total := atomic.LoadInt64(s.total)
+		if total <= 0 {
+			return ""
+		}
+
+		pct := float64(value) / float64(total) * 100
+
+		eta := "?"
+		switch {
+		case value >= total:
+			eta = "done"
+		case rate > 0:
+			remaining := total - value
+			eta = time.Duration(float64(remaining) / rate * float64(time.Second)).Round(time.Second).String()
+		}
+
+		return fmt.Sprintf("%d/%d (%.0f%%) ETA %s", value, total, pct, eta)
+
+	default:
+		return ""
+	}
+}
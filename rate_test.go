@@ -0,0 +1,93 @@
+package dspc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterRateColumn(t *testing.T) {
+	var progress Progress
+	progress.Register("items", Rate)
+	progress.Inc("items", 10)
+
+	var out strings.Builder
+	if err := progress.prettyPrint(&out, "Progress:", false); err != nil {
+		t.Fatalf("prettyPrint: %v", err)
+	}
+	if !strings.Contains(out.String(), "/s") {
+		t.Fatalf("expected a rate column for a Rate key, got:\n%s", out.String())
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	progress.Inc("items", 10)
+
+	out.Reset()
+	if err := progress.prettyPrint(&out, "Progress:", false); err != nil {
+		t.Fatalf("prettyPrint: %v", err)
+	}
+	if !strings.Contains(out.String(), "/s") {
+		t.Fatalf("expected a rate column after the second tick, got:\n%s", out.String())
+	}
+}
+
+func TestSetTotalShowsProgressAndETA(t *testing.T) {
+	var progress Progress
+	progress.Inc("done", 25)
+	progress.SetTotal("done", 100)
+
+	expectValue(t, progress.Get("done"), 25)
+
+	var out strings.Builder
+	if err := progress.prettyPrint(&out, "Progress:", false); err != nil {
+		t.Fatalf("prettyPrint: %v", err)
+	}
+	// A single tick has no rate yet, so the ETA is unknown.
+	if !strings.Contains(out.String(), "25/100 (25%) ETA ?") {
+		t.Fatalf("expected done/total/percentage and ETA, got:\n%s", out.String())
+	}
+}
+
+func TestSetTotalDone(t *testing.T) {
+	var progress Progress
+	progress.SetTotal("done", 10)
+	progress.Inc("done", 10)
+
+	var out strings.Builder
+	if err := progress.prettyPrint(&out, "Progress:", false); err != nil {
+		t.Fatalf("prettyPrint: %v", err)
+	}
+	if !strings.Contains(out.String(), "ETA done") {
+		t.Fatalf("expected ETA done once value reaches total, got:\n%s", out.String())
+	}
+}
+
+func TestSetTotalPromotesGauge(t *testing.T) {
+	var progress Progress
+	progress.Register("done", Gauge)
+	progress.SetTotal("done", 100)
+	progress.Inc("done", 25)
+
+	var out strings.Builder
+	if err := progress.prettyPrint(&out, "Progress:", false); err != nil {
+		t.Fatalf("prettyPrint: %v", err)
+	}
+	if !strings.Contains(out.String(), "25/100 (25%)") {
+		t.Fatalf("expected SetTotal to promote a Gauge key to Total, got:\n%s", out.String())
+	}
+}
+
+func TestCounterAndGaugeHaveNoExtraColumn(t *testing.T) {
+	var progress Progress
+	progress.Inc("plain", 5)
+	progress.Register("gauge", Gauge)
+	progress.Inc("gauge", 5)
+
+	var out strings.Builder
+	if err := progress.prettyPrint(&out, "Progress:", false); err != nil {
+		t.Fatalf("prettyPrint: %v", err)
+	}
+
+	const expected = "\033[J\nProgress:\n  gauge  5\n  plain  5\n\n"
+	expectValue(t, out.String(), expected)
+}
@@ -0,0 +1,40 @@
+// Package redistransport implements dspc.Transport on top of Redis Pub/Sub.
+//
+// It lives in its own module so the core dspc package stays dependency-free:
+// importing dspc alone never pulls in go-redis. Only programs that actually
+// want multi-process aggregation over Redis need to depend on this package.
+package redistransport
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Transport implements dspc.Transport on top of Redis Pub/Sub, letting
+// dspc.Progress.Publish and dspc.Aggregator share counters across machines or
+// containers without inventing a bespoke RPC layer.
+type Transport struct {
+	client  *redis.Client
+	channel string
+}
+
+// New returns a Transport that publishes to and subscribes on the given
+// Redis Pub/Sub channel. The caller owns client's lifecycle.
+func New(client *redis.Client, channel string) *Transport {
+	return &Transport{client: client, channel: channel}
+}
+
+func (t *Transport) Publish(data []byte) error {
+	return t.client.Publish(context.Background(), t.channel, data).Err()
+}
+
+func (t *Transport) Subscribe(fn func(data []byte)) {
+	sub := t.client.Subscribe(context.Background(), t.channel)
+
+	go func() {
+		for msg := range sub.Channel() {
+			fn([]byte(msg.Payload))
+		}
+	}()
+}
@@ -0,0 +1,186 @@
+package dspc
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// AutoFileOptions configures AutoFile.
+type AutoFileOptions struct {
+	// MaxSize rotates the file once writing to it would grow it past this
+	// many bytes. Zero disables size-based rotation.
+	MaxSize int64
+
+	// MaxAge rotates the file once it's been open for longer than this
+	// duration. Zero disables age-based rotation.
+	MaxAge time.Duration
+
+	// MaxBackups is how many rotated copies (path.1, path.2, ...) to keep
+	// around. Older copies are removed as new ones are created. Zero keeps
+	// every backup, each named with a timestamp suffix instead of a number.
+	MaxBackups int
+
+	// ReopenOnSIGHUP reopens the file on SIGHUP, so an external log rotator
+	// such as logrotate can rename the file out from under a long-running
+	// process.
+	ReopenOnSIGHUP bool
+}
+
+// AutoFile is an io.Writer over a file on disk, suitable to pass to
+// PrettyPrintEveryWithOptions (InPlaceAuto keeps the cursor-up escape out of
+// the file; PrettyPrintEvery always writes it) for long-running batch jobs.
+// Depending on Options it
+// rotates the file itself by size and/or age, and/or reopens it on SIGHUP
+// so external tools like logrotate work.
+type AutoFile struct {
+	path string
+	opts AutoFileOptions
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	sig   chan os.Signal
+	close chan struct{}
+}
+
+// NewAutoFile opens path for appending and returns a writer over it,
+// rotating and/or reopening per opts.
+func NewAutoFile(path string, opts AutoFileOptions) (*AutoFile, error) {
+	f := &AutoFile{path: path, opts: opts}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	if opts.ReopenOnSIGHUP {
+		f.sig = make(chan os.Signal, 1)
+		f.close = make(chan struct{})
+		signal.Notify(f.sig, syscall.SIGHUP)
+
+		go f.watchSIGHUP()
+	}
+
+	return f, nil
+}
+
+func (f *AutoFile) watchSIGHUP() {
+	for {
+		select {
+		case <-f.sig:
+			f.mu.Lock()
+			err := f.reopen()
+			f.mu.Unlock()
+
+			if err != nil {
+				// Should be rare (e.g. a permission hiccup racing with
+				// logrotate); the old fd is still in use, so progress
+				// output keeps working and will pick up the new file on
+				// the next SIGHUP.
+				fmt.Fprintln(os.Stderr, "Error reopening progress file:", err)
+			}
+		case <-f.close:
+			return
+		}
+	}
+}
+
+func (f *AutoFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = fi.Size()
+	f.opened = time.Now()
+	return nil
+}
+
+// reopen opens a fresh file descriptor at path for SIGHUP handling. The
+// descriptor currently in use is only closed once the new one is confirmed
+// open, so a failed reopen (e.g. a permission hiccup racing with logrotate)
+// leaves Write still working against the old one instead of a closed fd.
+func (f *AutoFile) reopen() error {
+	old := f.file
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// rotate closes the current file and renames it out of the way, then opens
+// a fresh file at path.
+//
+// With MaxBackups > 0, existing numbered backups (path.1, path.2, ...) are
+// shifted down by one and the oldest is removed, bounding how many are kept.
+// With MaxBackups == 0 (the default), every backup is kept: each gets its
+// own timestamp suffix instead of a shared, reused path.1 slot, so rotating
+// repeatedly doesn't clobber the previous backup.
+func (f *AutoFile) rotate() error {
+	if f.file != nil {
+		f.file.Close()
+	}
+
+	if f.opts.MaxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", f.path, f.opts.MaxBackups))
+
+		for i := f.opts.MaxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+		}
+
+		os.Rename(f.path, f.path+".1")
+	} else {
+		os.Rename(f.path, fmt.Sprintf("%s.%s", f.path, time.Now().Format("20060102T150405.000000000")))
+	}
+
+	return f.open()
+}
+
+// Write implements io.Writer. It rotates the file first if p would push it
+// past MaxSize, or if it's older than MaxAge.
+func (f *AutoFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	needsRotation := (f.opts.MaxAge > 0 && time.Since(f.opened) >= f.opts.MaxAge) ||
+		(f.opts.MaxSize > 0 && f.size+int64(len(p)) > f.opts.MaxSize)
+
+	if needsRotation {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close stops SIGHUP handling (if enabled) and closes the underlying file.
+func (f *AutoFile) Close() error {
+	if f.close != nil {
+		signal.Stop(f.sig)
+		close(f.close)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,107 @@
+package dspc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAutoFileRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.log")
+
+	f, err := NewAutoFile(path, AutoFileOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewAutoFile: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("12345")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := f.Write([]byte("678901")); err != nil { // pushes past MaxSize, rotates first
+		t.Fatalf("Write: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected backup %s.1 to exist: %v", path, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	expectValue(t, string(data), "678901")
+}
+
+func TestAutoFileKeepsEveryBackupByDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.log")
+
+	f, err := NewAutoFile(path, AutoFileOptions{MaxSize: 1})
+	if err != nil {
+		t.Fatalf("NewAutoFile: %v", err)
+	}
+	defer f.Close()
+
+	for range 3 {
+		if _, err := f.Write([]byte("xx")); err != nil { // always exceeds MaxSize, always rotates
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 distinct backups, got %v", matches)
+	}
+}
+
+func TestAutoFileReopenFailureKeepsOldFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.log")
+
+	f, err := NewAutoFile(path, AutoFileOptions{})
+	if err != nil {
+		t.Fatalf("NewAutoFile: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("a")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// Force the next reopen to fail deterministically: opening a directory
+	// with O_WRONLY always errors, regardless of permissions.
+	f.path = dir
+	if err := f.reopen(); err == nil {
+		t.Fatalf("expected reopen to fail when path is a directory")
+	}
+
+	// The old descriptor must still be open and usable.
+	if _, err := f.file.Write([]byte("b")); err != nil {
+		t.Fatalf("expected old file descriptor to still work, got: %v", err)
+	}
+}
+
+func TestAutoFileWritesAppend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.log")
+
+	f, err := NewAutoFile(path, AutoFileOptions{})
+	if err != nil {
+		t.Fatalf("NewAutoFile: %v", err)
+	}
+
+	f.Write([]byte("a"))
+	f.Write([]byte("b"))
+	f.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	expectValue(t, string(data), "ab")
+}
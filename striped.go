@@ -0,0 +1,75 @@
+package dspc
+
+import (
+	"runtime"
+	"sync/atomic"
+	_ "unsafe" // for go:linkname
+)
+
+// cacheLineSize is used to pad striped counter cells so each lives on its
+// own cache line, avoiding false sharing between goroutines writing to
+// adjacent cells.
+const cacheLineSize = 64
+
+type stripedCell struct {
+	v int64
+	_ [cacheLineSize - 8]byte
+}
+
+// stripedCounter is a LongAdder-style counter: an array of padded cells
+// that writers spread across to avoid contending on a single cache line.
+// Reads sum over all cells.
+type stripedCounter struct {
+	cells []stripedCell
+}
+
+func newStripedCounter(n int) *stripedCounter {
+	return &stripedCounter{cells: make([]stripedCell, n)}
+}
+
+// fastrand is the runtime's per-goroutine PRNG. It's linked in directly so
+// cell selection needs no shared, coordinated state: a round-robin counter
+// would just relocate the contention IncStriped is meant to eliminate onto
+// whatever picks the cell.
+//
+//go:linkname fastrand runtime.fastrand
+func fastrand() uint32
+
+func (s *stripedCounter) add(delta int64) {
+	n := uint64(len(s.cells))
+	i := uint64(fastrand()) % n
+
+	for {
+		cell := &s.cells[i]
+		old := atomic.LoadInt64(&cell.v)
+		if atomic.CompareAndSwapInt64(&cell.v, old, old+delta) {
+			return
+		}
+
+		// Lost the race for this cell; retry on the next one instead of
+		// spinning on the same contended cache line.
+		i = (i + 1) % n
+	}
+}
+
+func (s *stripedCounter) sum() int64 {
+	var total int64
+	for i := range s.cells {
+		total += atomic.LoadInt64(&s.cells[i].v)
+	}
+	return total
+}
+
+// defaultStripeCount picks a stripe width that scales with the number of
+// runnable OS threads, capped to keep memory use bounded for jobs with many
+// striped keys.
+func defaultStripeCount() int {
+	n := runtime.GOMAXPROCS(0)
+	if n < 1 {
+		n = 1
+	}
+	if n > 32 {
+		n = 32
+	}
+	return n
+}
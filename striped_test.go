@@ -0,0 +1,50 @@
+package dspc
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIncStriped(t *testing.T) {
+	var progress Progress
+
+	progress.IncStriped("hot", 5)
+	progress.IncStriped("hot", -2)
+	expectValue(t, progress.Get("hot"), 3)
+
+	// Inc on an already-striped key keeps working and keeps the total correct.
+	progress.Inc("hot", 10)
+	expectValue(t, progress.Get("hot"), 13)
+}
+
+func TestIncStripedPreservesValueOnPromotion(t *testing.T) {
+	var progress Progress
+
+	progress.Inc("key", 7)
+	expectValue(t, progress.Get("key"), 7)
+
+	// First IncStriped call promotes the plain slot, keeping its value.
+	progress.IncStriped("key", 3)
+	expectValue(t, progress.Get("key"), 10)
+}
+
+func TestIncStripedConcurrent(t *testing.T) {
+	var progress Progress
+
+	concurrency := 50
+	perGoroutine := 1000
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				progress.IncStriped("hot", 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	expectValue(t, progress.Get("hot"), int64(concurrency*perGoroutine))
+}